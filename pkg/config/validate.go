@@ -0,0 +1,211 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// wildcard mirrors the "*" segment types.Builder.buildResource inserts into
+// a field's Terraform path for every TypeList/TypeSet/TypeMap level it
+// descends into (see its tfPaths/xpPaths construction).
+const wildcard = "*"
+
+// PathError describes a Terraform field path configured on a Resource (via
+// References or LateInitializer.IgnoredFields; Sensitive paths are not
+// checked) that does not match any field in that resource's Terraform
+// schema - typically because of a typo. With Source "example" it instead
+// describes an example directory that failed to load.
+type PathError struct {
+	// Source names the Resource field the offending path came from, e.g.
+	// "references" or "lateInitializer.ignoredFields", or is "example" for
+	// an example directory that failed to load.
+	Source string
+	// Path is the unknown Terraform path as configured, or the example
+	// directory when Source is "example".
+	Path string
+	// Suggestion is the closest known path, chosen by Levenshtein distance,
+	// or empty if the resource's schema has no fields at all. When Source
+	// is "example" it holds the load error instead.
+	Suggestion string
+}
+
+func (e PathError) String() string {
+	if e.Source == "example" {
+		return fmt.Sprintf("%s: cannot load %q: %s", e.Source, e.Path, e.Suggestion)
+	}
+	if e.Suggestion == "" {
+		return fmt.Sprintf("%s: unknown path %q", e.Source, e.Path)
+	}
+	return fmt.Sprintf("%s: unknown path %q, did you mean %q?", e.Source, e.Path, e.Suggestion)
+}
+
+// ValidatePaths checks that every Terraform path configured on r's
+// References and LateInitializer.IgnoredFields refers to a real field in
+// r.TerraformResource's schema, returning one PathError per unknown path
+// with the nearest known path as a suggestion. This is what
+// types.Builder.buildResource's long-standing comment on IgnoredFields
+// refers to: today a typo in one of these strings silently no-ops instead
+// of failing, producing a subtly wrong CRD.
+//
+// If exampleDir is non-empty, it is additionally loaded with
+// hashicorp/terraform-config-inspect/tfconfig as a sanity check that the
+// example configuration these paths were likely copied from still parses.
+func ValidatePaths(r *Resource, exampleDir string) []PathError {
+	var errs []PathError
+
+	if exampleDir != "" {
+		if _, diags := tfconfig.LoadModule(exampleDir); diags.HasErrors() {
+			errs = append(errs, PathError{Source: "example", Path: exampleDir, Suggestion: diags.Err().Error()})
+		}
+	}
+
+	known := allFieldPaths(r.TerraformResource, nil)
+
+	for p := range r.References {
+		if known[p] {
+			continue
+		}
+		errs = append(errs, PathError{Source: "references", Path: p, Suggestion: nearestPath(p, known)})
+	}
+	for _, p := range r.LateInitializer.IgnoredFields {
+		if known[p] {
+			continue
+		}
+		errs = append(errs, PathError{Source: "lateInitializer.ignoredFields", Path: p, Suggestion: nearestPath(p, known)})
+	}
+	// Note: cfg.Sensitive's field paths are derived from the schema itself
+	// (types.Builder.buildResource populates them as it walks sch.Sensitive
+	// fields), so they cannot go stale the way a hand-written path can;
+	// nothing to validate there today.
+
+	return errs
+}
+
+// allFieldPaths returns every Terraform field path reachable from res,
+// recursing into nested Elem resources/schemas, in the fieldPath()
+// representation below - exactly the one types.Builder.buildResource
+// matches cfg.References and cfg.LateInitializer.IgnoredFields keys
+// against.
+func allFieldPaths(res *schema.Resource, prefix []string) map[string]bool {
+	paths := make(map[string]bool)
+	if res == nil {
+		return paths
+	}
+	for name, sch := range res.Schema {
+		p := appendPath(prefix, name)
+		paths[fieldPath(p)] = true
+		collectSchemaPaths(sch, p, paths)
+	}
+	return paths
+}
+
+// collectSchemaPaths adds the paths reachable below sch, whose own path
+// (pre-fieldPath()) is p, to paths.
+func collectSchemaPaths(sch *schema.Schema, p []string, paths map[string]bool) {
+	switch sch.Type {
+	case schema.TypeList, schema.TypeSet, schema.TypeMap:
+		wp := appendPath(p, wildcard)
+		switch et := sch.Elem.(type) {
+		case *schema.Resource:
+			for name, nested := range et.Schema {
+				np := appendPath(wp, name)
+				paths[fieldPath(np)] = true
+				collectSchemaPaths(nested, np, paths)
+			}
+		case *schema.Schema:
+			collectSchemaPaths(et, wp, paths)
+		}
+	}
+}
+
+// appendPath returns a new slice with name appended to prefix, never
+// aliasing prefix's backing array, since the same prefix is reused across
+// sibling schema fields.
+func appendPath(prefix []string, name string) []string {
+	p := make([]string, len(prefix), len(prefix)+1)
+	copy(p, prefix)
+	return append(p, name)
+}
+
+// fieldPath renders parts the same way types.Builder's unexported fieldPath
+// helper does: each "*" wildcard segment is skipped rather than dropped,
+// which - because fieldpath.Segments.String() does not collapse a skipped
+// segment away - yields e.g. "lifecycle_rule..transition..days" for a field
+// nested two TypeList/TypeSet/TypeMap levels deep, not
+// "lifecycle_rule.*.transition.*.days". pkg/types already imports this
+// package, so that helper can't be reused directly; this copy must be kept
+// in sync with it.
+func fieldPath(parts []string) string {
+	seg := make(fieldpath.Segments, len(parts))
+	for i, p := range parts {
+		if p == wildcard {
+			continue
+		}
+		seg[i] = fieldpath.Field(p)
+	}
+	return seg.String()
+}
+
+// nearestPath returns the known path closest to path by Levenshtein
+// distance, or "" if known is empty.
+func nearestPath(path string, known map[string]bool) string {
+	best, bestDist := "", -1
+	for k := range known {
+		d := levenshtein(path, k)
+		if bestDist == -1 || d < bestDist {
+			best, bestDist = k, d
+		}
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := 0; j <= len(b); j++ {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min3(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// nestedListResource returns a schema.Resource with a field nested two
+// TypeList levels deep, i.e. lifecycle_rule[].transition[].days.
+func nestedListResource() *schema.Resource {
+	return &schema.Resource{Schema: map[string]*schema.Schema{
+		"lifecycle_rule": {
+			Type: schema.TypeList,
+			Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+				"transition": {
+					Type: schema.TypeList,
+					Elem: &schema.Resource{Schema: map[string]*schema.Schema{
+						"days": {Type: schema.TypeInt},
+					}},
+				},
+			}},
+		},
+	}}
+}
+
+// TestValidatePathsNested checks that a nested reference/ignored-field path
+// is accepted when given in the representation types.Builder.buildResource
+// actually matches against (fieldPath(), which skips "*" wildcard segments
+// rather than keeping them literal), and rejected otherwise, so that
+// ValidatePaths and buildResource never disagree about a correctly
+// configured nested path.
+func TestValidatePathsNested(t *testing.T) {
+	matched := fieldPath([]string{"lifecycle_rule", wildcard, "transition", wildcard, "days"})
+
+	r := &Resource{
+		TerraformResource: nestedListResource(),
+		References:        map[string]string{matched: "SomeKind"},
+	}
+	if errs := ValidatePaths(r, ""); len(errs) != 0 {
+		t.Fatalf("expected no errors for a correctly-represented nested reference path, got: %v", errs)
+	}
+
+	r = &Resource{
+		TerraformResource: nestedListResource(),
+		LateInitializer:   LateInitializer{IgnoredFields: []string{matched}},
+	}
+	if errs := ValidatePaths(r, ""); len(errs) != 0 {
+		t.Fatalf("expected no errors for a correctly-represented nested ignored field, got: %v", errs)
+	}
+
+	literalWildcard := "lifecycle_rule.*.transition.*.days"
+	r = &Resource{
+		TerraformResource: nestedListResource(),
+		References:        map[string]string{literalWildcard: "SomeKind"},
+	}
+	errs := ValidatePaths(r, "")
+	if len(errs) != 1 {
+		t.Fatalf("expected the literal '*' form to be flagged as unknown, got: %v", errs)
+	}
+}
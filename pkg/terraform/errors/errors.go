@@ -26,10 +26,13 @@ import (
 
 const (
 	levelError = "error"
+	levelWarn  = "warn"
+	levelInfo  = "info"
 )
 
 type tfError struct {
-	message string
+	message     string
+	diagnostics []Diagnostic
 }
 
 type applyFailed struct {
@@ -46,51 +49,234 @@ type TerraformLog struct {
 // LogDiagnostic represents relevant fields of a Terraform CLI JSON-formatted
 // log line diagnostic info
 type LogDiagnostic struct {
-	Severity string `json:"severity"`
-	Summary  string `json:"summary"`
-	Detail   string `json:"detail"`
-	Range    Range  `json:"range"`
+	Severity string  `json:"severity"`
+	Summary  string  `json:"summary"`
+	Detail   string  `json:"detail"`
+	Range    Range   `json:"range"`
+	Snippet  Snippet `json:"snippet"`
 }
 
-// Range represents a line range in a Terraform workspace file
+// Pos is a position within a Terraform configuration file, as emitted by
+// Terraform's JSON log format.
+type Pos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Byte   int `json:"byte"`
+}
+
+// Range represents a line/column range in a Terraform workspace file
 type Range struct {
 	FileName string `json:"filename"`
+	Start    Pos    `json:"start"`
+	End      Pos    `json:"end"`
+}
+
+// Snippet represents the raw source snippet Terraform attaches to a
+// diagnostic so that the offending configuration can be shown without
+// re-reading the workspace file.
+type Snippet struct {
+	Context              string `json:"context"`
+	Code                 string `json:"code"`
+	StartLine            int    `json:"start_line"`
+	HighlightStartOffset int    `json:"highlight_start_offset"`
+	HighlightEndOffset   int    `json:"highlight_end_offset"`
+}
+
+// Diagnostic is a structured Terraform diagnostic, i.e. the parsed
+// equivalent of LogDiagnostic that is exposed to callers of this package.
+type Diagnostic struct {
+	Severity string
+	Summary  string
+	Detail   string
+	Range    Range
+	Snippet  Snippet
+}
+
+// toDiagnostic converts a LogDiagnostic into the Diagnostic type exposed to
+// callers.
+func (l LogDiagnostic) toDiagnostic() Diagnostic {
+	return Diagnostic{
+		Severity: l.Severity,
+		Summary:  l.Summary,
+		Detail:   l.Detail,
+		Range:    l.Range,
+		Snippet:  l.Snippet,
+	}
+}
+
+// toDiagnostic converts a TerraformLog line into a Diagnostic, falling back
+// to the line's plain message and level when it carries no diagnostic block
+// of its own (e.g. a bare "error" level log line). The diagnostic's own
+// severity (e.g. "warning") is kept as-is rather than being replaced by the
+// coarser log line level (e.g. "warn"), so that HasSeverity matches what
+// Terraform actually reported.
+func (l *TerraformLog) toDiagnostic() Diagnostic {
+	d := l.Diagnostic.toDiagnostic()
+	if d.Summary == "" {
+		d.Summary = l.Message
+	}
+	if d.Severity == "" {
+		d.Severity = l.Level
+	}
+	return d
 }
 
 func (t *tfError) Error() string {
 	return t.message
 }
 
-func newTFError(message string, logs []byte) (string, *tfError) {
-	tfError := &tfError{
-		message: message,
+// Diagnostics returns the structured diagnostics attached to this error, if
+// any.
+func (t *tfError) Diagnostics() []Diagnostic {
+	return t.diagnostics
+}
+
+// diagnosticsError is implemented by errors returned by this package that
+// carry structured Terraform diagnostics.
+type diagnosticsError interface {
+	error
+	Diagnostics() []Diagnostic
+}
+
+// AllDiagnostics returns every diagnostic attached to err: the error-level
+// diagnostics it carries, plus any warnings and infos attached via
+// WithDiagnostics. It returns nil if err was not produced by this package
+// and carries none of the above.
+func AllDiagnostics(err error) []Diagnostic {
+	var all []Diagnostic
+	if d, ok := errors.Cause(err).(diagnosticsError); ok {
+		all = append(all, d.Diagnostics()...)
+	}
+	all = append(all, Warnings(err)...)
+	all = append(all, Infos(err)...)
+	return all
+}
+
+// HasSeverity returns true if err carries at least one diagnostic with the
+// given severity, e.g. "error" or "warning".
+func HasSeverity(err error, severity string) bool {
+	for _, d := range AllDiagnostics(err) {
+		if d.Severity == severity {
+			return true
+		}
 	}
+	return false
+}
 
+// ParseDiagnostics parses Terraform CLI JSON log output and buckets every
+// log line's diagnostic by the line's @level. Lines that carry neither an error,
+// warning nor info level (e.g. trace/debug) are ignored.
+func ParseDiagnostics(logs []byte) (errs []Diagnostic, warnings []Diagnostic, infos []Diagnostic, err error) {
 	tfLogs, err := parseTerraformLogs(logs)
 	if err != nil {
-		return err.Error(), tfError
+		return nil, nil, nil, err
 	}
-
-	messages := make([]string, 0, len(tfLogs))
 	for _, l := range tfLogs {
-		// only use error logs
-		if l == nil || l.Level != levelError {
+		if l == nil {
 			continue
 		}
-		m := l.Message
-		if l.Diagnostic.Severity == levelError && l.Diagnostic.Summary != "" {
-			m = fmt.Sprintf("%s: %s", l.Diagnostic.Summary, l.Diagnostic.Detail)
-			if len(l.Diagnostic.Range.FileName) != 0 {
-				m = m + ": File name: " + l.Diagnostic.Range.FileName
-			}
+		d := l.toDiagnostic()
+		switch l.Level {
+		case levelError:
+			errs = append(errs, d)
+		case levelWarn:
+			warnings = append(warnings, d)
+		case levelInfo:
+			infos = append(infos, d)
 		}
-		messages = append(messages, m)
 	}
-	if len(messages) == 0 {
-		return "", nil
+	return errs, warnings, infos, nil
+}
+
+func newTFError(message string, logs []byte) (string, *tfError, []Diagnostic, []Diagnostic) {
+	tfError := &tfError{
+		message: message,
+	}
+
+	diagnostics, warnings, infos, err := ParseDiagnostics(logs)
+	if err != nil {
+		return err.Error(), tfError, nil, nil
+	}
+
+	if len(diagnostics) == 0 {
+		if len(warnings) == 0 && len(infos) == 0 {
+			return "", nil, nil, nil
+		}
+		// No error-level diagnostic was found, but the run still surfaced
+		// warnings or infos (e.g. deprecation notices); keep tfError around
+		// with its generic message so they aren't silently dropped.
+		return "", tfError, warnings, infos
+	}
+
+	messages := make([]string, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		m := d.Summary
+		if d.Detail != "" {
+			m = fmt.Sprintf("%s: %s", d.Summary, d.Detail)
+		}
+		if len(d.Range.FileName) != 0 {
+			m = m + ": File name: " + d.Range.FileName
+		}
+		messages = append(messages, m)
 	}
+	tfError.diagnostics = diagnostics
 	tfError.message = fmt.Sprintf("%s: %s", message, strings.Join(messages, "\n"))
-	return "", tfError
+	return "", tfError, warnings, infos
+}
+
+// WithDiagnostics wraps an error with the non-error Terraform diagnostics
+// (warnings and informational messages) that accompanied it, so that
+// callers can surface them (e.g. as Kubernetes events or CR conditions)
+// without losing the original error semantics.
+type WithDiagnostics struct {
+	error
+	warnings []Diagnostic
+	infos    []Diagnostic
+}
+
+// Unwrap returns the wrapped error.
+func (w *WithDiagnostics) Unwrap() error {
+	return w.error
+}
+
+// Cause returns the wrapped error.
+func (w *WithDiagnostics) Cause() error {
+	return w.error
+}
+
+// Warnings returns the warning-level diagnostics attached to this error.
+func (w *WithDiagnostics) Warnings() []Diagnostic {
+	return w.warnings
+}
+
+// Infos returns the info-level diagnostics attached to this error.
+func (w *WithDiagnostics) Infos() []Diagnostic {
+	return w.infos
+}
+
+func withDiagnostics(err error, warnings, infos []Diagnostic) error {
+	if len(warnings) == 0 && len(infos) == 0 {
+		return err
+	}
+	return &WithDiagnostics{error: err, warnings: warnings, infos: infos}
+}
+
+// Warnings returns the warning-level diagnostics attached to err, if any.
+func Warnings(err error) []Diagnostic {
+	w := &WithDiagnostics{}
+	if !errors.As(err, &w) {
+		return nil
+	}
+	return w.warnings
+}
+
+// Infos returns the info-level diagnostics attached to err, if any.
+func Infos(err error) []Diagnostic {
+	w := &WithDiagnostics{}
+	if !errors.As(err, &w) {
+		return nil
+	}
+	return w.infos
 }
 
 func parseTerraformLogs(logs []byte) ([]*TerraformLog, error) {
@@ -112,15 +298,16 @@ func parseTerraformLogs(logs []byte) ([]*TerraformLog, error) {
 
 // NewApplyFailed returns a new apply failure error with given logs.
 func NewApplyFailed(logs []byte) error {
-	parseError, tfError := newTFError("apply failed", logs)
+	parseError, tfError, warnings, infos := newTFError("apply failed", logs)
 	if tfError == nil {
 		return nil
 	}
 	result := &applyFailed{tfError: tfError}
-	if parseError == "" {
-		return result
+	var out error = result
+	if parseError != "" {
+		out = errors.WithMessage(result, parseError)
 	}
-	return errors.WithMessage(result, parseError)
+	return withDiagnostics(out, warnings, infos)
 }
 
 // IsApplyFailed returns whether error is due to failure of an apply operation.
@@ -135,15 +322,16 @@ type destroyFailed struct {
 
 // NewDestroyFailed returns a new destroy failure error with given logs.
 func NewDestroyFailed(logs []byte) error {
-	parseError, tfError := newTFError("destroy failed", logs)
+	parseError, tfError, warnings, infos := newTFError("destroy failed", logs)
 	if tfError == nil {
 		return nil
 	}
 	result := &destroyFailed{tfError: tfError}
-	if parseError == "" {
-		return result
+	var out error = result
+	if parseError != "" {
+		out = errors.WithMessage(result, parseError)
 	}
-	return errors.WithMessage(result, parseError)
+	return withDiagnostics(out, warnings, infos)
 }
 
 // IsDestroyFailed returns whether error is due to failure of a destroy operation.
@@ -158,15 +346,16 @@ type refreshFailed struct {
 
 // NewRefreshFailed returns a new destroy failure error with given logs.
 func NewRefreshFailed(logs []byte) error {
-	parseError, tfError := newTFError("refresh failed", logs)
+	parseError, tfError, warnings, infos := newTFError("refresh failed", logs)
 	if tfError == nil {
 		return nil
 	}
 	result := &refreshFailed{tfError: tfError}
-	if parseError == "" {
-		return result
+	var out error = result
+	if parseError != "" {
+		out = errors.WithMessage(result, parseError)
 	}
-	return errors.WithMessage(result, parseError)
+	return withDiagnostics(out, warnings, infos)
 }
 
 // IsRefreshFailed returns whether error is due to failure of a destroy operation.
@@ -181,15 +370,16 @@ type planFailed struct {
 
 // NewPlanFailed returns a new destroy failure error with given logs.
 func NewPlanFailed(logs []byte) error {
-	parseError, tfError := newTFError("plan failed", logs)
+	parseError, tfError, warnings, infos := newTFError("plan failed", logs)
 	if tfError == nil {
 		return nil
 	}
 	result := &planFailed{tfError: tfError}
-	if parseError == "" {
-		return result
+	var out error = result
+	if parseError != "" {
+		out = errors.WithMessage(result, parseError)
 	}
-	return errors.WithMessage(result, parseError)
+	return withDiagnostics(out, warnings, infos)
 }
 
 // IsPlanFailed returns whether error is due to failure of a destroy operation.
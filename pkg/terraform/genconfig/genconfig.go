@@ -0,0 +1,222 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package genconfig generates Terraform HCL configuration and a matching
+// import block from a Crossplane CR's spec.forProvider, so that an existing
+// cloud resource can be adopted with `terraform plan -generate-config-out`
+// instead of relying on the provider's implicit refresh on first reconcile.
+package genconfig
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/terrajet/pkg/config"
+)
+
+// Generate returns the HCL `resource` block for cfg, populated from params
+// (typically a CR's spec.forProvider, already decoded from its JSON
+// representation), together with an `import` block that binds the resource
+// to externalName. A required sensitive field has no zero value Terraform
+// will accept, so it is populated with a reference to a `variable` declared
+// alongside the resource; the caller is expected to supply a value for it,
+// typically from the connection secret, before running `terraform plan`.
+func Generate(cfg *config.Resource, resourceLabel, externalName string, params map[string]interface{}) (string, error) {
+	var body strings.Builder
+	vars := map[string]bool{}
+	if err := writeBlock(&body, "  ", cfg.TerraformResource, params, vars); err != nil {
+		return "", errors.Wrapf(err, "cannot generate configuration for %s.%s", cfg.Name, resourceLabel)
+	}
+
+	var out strings.Builder
+	for _, v := range sortedVarNames(vars) {
+		fmt.Fprintf(&out, "variable %q {\n  type      = string\n  sensitive = true\n}\n\n", v)
+	}
+	fmt.Fprintf(&out, "resource %q %q {\n%s}\n\n", cfg.Name, resourceLabel, body.String())
+	fmt.Fprintf(&out, "import {\n  to = %s.%s\n  id = %q\n}\n", cfg.Name, resourceLabel, externalName)
+	return out.String(), nil
+}
+
+// writeBlock walks res the same way types.Builder.buildResource does,
+// writing one HCL attribute or nested block per schema field that has a
+// corresponding entry in params; an optional field whose entry is its
+// type's zero value is omitted, a required one is written. A required
+// sensitive field is written even without an entry. It records the name of every
+// `variable` a required sensitive field referenced into vars, so Generate
+// can declare it.
+func writeBlock(b *strings.Builder, indent string, res *schema.Resource, params map[string]interface{}, vars map[string]bool) error {
+	for _, k := range sortedKeys(res.Schema) {
+		sch := res.Schema[k]
+
+		// Computed-only fields are populated by Terraform itself and must
+		// never appear in generated configuration.
+		if sch.Computed && !sch.Optional {
+			continue
+		}
+
+		if sch.Sensitive {
+			// A sensitive field's real value never appears in params: it is
+			// stored in a Secret and the CR spec only holds a reference to
+			// it (see types.Builder's "SecretRef" field rewriting), so
+			// params[k] is never populated for these. An optional sensitive
+			// field can still simply be omitted, same as any other optional
+			// field absent from the CR spec.
+			if sch.Optional {
+				continue
+			}
+			varName := fmt.Sprintf("sensitive_%s", k)
+			vars[varName] = true
+			fmt.Fprintf(b, "%s%s = sensitive(var.%s)\n", indent, k, varName)
+			continue
+		}
+
+		v, ok := params[k]
+		if !ok || v == nil {
+			// Optional fields absent from the CR spec are omitted so
+			// Terraform can fill in the provider's server-side default.
+			continue
+		}
+		if sch.Optional && isZero(v) {
+			// Optional fields present in the CR spec with their type's zero
+			// value are omitted the same way an absent one is: there is no
+			// way to distinguish "explicitly set to the zero value" from
+			// "never set" once it has round-tripped through the CR spec, and
+			// treating the latter as the common case lets Terraform apply
+			// the provider's server-side default instead of overwriting it.
+			continue
+		}
+
+		if err := writeField(b, indent, k, sch, v, vars); err != nil {
+			return errors.Wrapf(err, "cannot generate field %s", k)
+		}
+	}
+	return nil
+}
+
+// isZero reports whether v - a decoded JSON scalar or collection - is its
+// type's zero value.
+func isZero(v interface{}) bool {
+	switch val := v.(type) {
+	case string:
+		return val == ""
+	case bool:
+		return !val
+	case float64:
+		return val == 0
+	case []interface{}:
+		return len(val) == 0
+	case map[string]interface{}:
+		return len(val) == 0
+	default:
+		return false
+	}
+}
+
+func writeField(b *strings.Builder, indent, key string, sch *schema.Schema, v interface{}, vars map[string]bool) error {
+	switch sch.Type {
+	case schema.TypeList, schema.TypeSet:
+		items, ok := v.([]interface{})
+		if !ok {
+			return errors.Errorf("expected a list for field %s, got %T", key, v)
+		}
+		if res, ok := sch.Elem.(*schema.Resource); ok {
+			for _, item := range items {
+				m, ok := item.(map[string]interface{})
+				if !ok {
+					return errors.Errorf("expected an object in list %s, got %T", key, item)
+				}
+				fmt.Fprintf(b, "%s%s {\n", indent, key)
+				if err := writeBlock(b, indent+"  ", res, m, vars); err != nil {
+					return err
+				}
+				fmt.Fprintf(b, "%s}\n", indent)
+			}
+			return nil
+		}
+		vals := make([]string, 0, len(items))
+		for _, item := range items {
+			vals = append(vals, hclLiteral(item))
+		}
+		fmt.Fprintf(b, "%s%s = [%s]\n", indent, key, strings.Join(vals, ", "))
+	case schema.TypeMap:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return errors.Errorf("expected a map for field %s, got %T", key, v)
+		}
+		fmt.Fprintf(b, "%s%s = {\n", indent, key)
+		for _, mk := range sortedMapKeys(m) {
+			fmt.Fprintf(b, "%s  %q = %s\n", indent, mk, hclLiteral(m[mk]))
+		}
+		fmt.Fprintf(b, "%s}\n", indent)
+	default:
+		fmt.Fprintf(b, "%s%s = %s\n", indent, key, hclLiteral(v))
+	}
+	return nil
+}
+
+// hclLiteral renders a decoded JSON value as an HCL literal. Every JSON
+// number decodes to a float64 regardless of whether it was written as an
+// integer, so a whole-valued float64 is rendered without a decimal point
+// (and never in %v's exponent form) to produce the plain integer syntax a
+// human editing this HCL by hand would have written.
+func hclLiteral(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return fmt.Sprintf("%q", val)
+	case float64:
+		if val == math.Trunc(val) {
+			return strconv.FormatInt(int64(val), 10)
+		}
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool, int, int64:
+		return fmt.Sprintf("%v", val)
+	default:
+		return fmt.Sprintf("%q", fmt.Sprintf("%v", val))
+	}
+}
+
+func sortedKeys(m map[string]*schema.Schema) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedMapKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedVarNames(vars map[string]bool) []string {
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -58,6 +58,14 @@ type Generated struct {
 type Builder struct {
 	Package *types.Package
 
+	// FailOnUnknownPaths opts in to failing Build when config.ValidatePaths
+	// finds a cfg.References or cfg.LateInitializer.IgnoredFields entry
+	// that does not match any field in the resource's schema. It defaults
+	// to false so that existing, already-working configurations are not
+	// broken by a path-matching edge case this package has not seen yet;
+	// generators that want the stricter behavior (e.g. in CI) can opt in.
+	FailOnUnknownPaths bool
+
 	genTypes []*types.Named
 	comments twtypes.Comments
 }
@@ -67,8 +75,28 @@ type typeWrapper struct {
 	isObservation bool
 }
 
-// Build returns parameters and observation types built out of Terraform schema.
+// Build returns parameters and observation types built out of Terraform
+// schema. If cfg.TerraformResourceV1 is set, it takes precedence: it is
+// converted to its v2 (terraform-plugin-sdk/v2) equivalent via
+// ConvertResource and stored into cfg.TerraformResource before generation
+// proceeds exactly as it would for a v2 schema, so that callers generating
+// types for a provider that has not migrated off the v1 SDK yet do not need
+// a separate entry point.
 func (g *Builder) Build(cfg *config.Resource) (Generated, error) {
+	if cfg.TerraformResourceV1 != nil {
+		converted, err := ConvertResource(cfg.TerraformResourceV1)
+		if err != nil {
+			return Generated{}, errors.Wrap(err, "cannot convert terraform-plugin-sdk v1 schema to v2")
+		}
+		cfg.TerraformResource = converted
+	}
+	if pathErrs := config.ValidatePaths(cfg, ""); len(pathErrs) > 0 && g.FailOnUnknownPaths {
+		msgs := make([]string, len(pathErrs))
+		for i, e := range pathErrs {
+			msgs[i] = e.String()
+		}
+		return Generated{}, errors.Errorf("cannot build the Types: invalid field paths in configuration of %s:\n%s", cfg.Kind, strings.Join(msgs, "\n"))
+	}
 	fp, ap, err := g.buildResource(cfg.TerraformResource, cfg, nil, nil, cfg.Kind)
 	return Generated{
 		Types:           g.genTypes,
@@ -160,21 +188,40 @@ func (g *Builder) buildResource(res *schema.Resource, cfg *config.Resource, tfPa
 				}
 				sfx := "SecretRef"
 				cfg.Sensitive.AddFieldPath(fieldPathWithWildcard(tfPaths), "spec.forProvider."+fieldPathWithWildcard(xpPaths)+sfx)
-				// todo(turkenh): do we need to support other field types as sensitive?
-				if fieldType.String() != "string" && fieldType.String() != "*string" {
-					return nil, nil, fmt.Errorf(`got type %q for field %q, only types "string" and "*string" supported as sensitive`, fieldType.String(), fieldNameCamel)
-				}
-				// Replace a parameter field with secretKeyRef if it is sensitive.
-				// If it is an observation field, it will be dropped.
-				// Data will be loaded from the referenced secret key.
+				// Replace a parameter field with a reference to the Kubernetes
+				// Secret it should be loaded from. If it is an observation
+				// field, it will have already been dropped above. Data will
+				// be loaded from the referenced secret at apply time - see
+				// the runtime's secret merge logic for how every entry of a
+				// map/list sensitive field is populated from there.
 				fieldNameCamel += sfx
-
 				tfTag = "-"
-				fieldType = typeSecretKeySelector
 				jsonTag = name.NewFromCamel(fieldNameCamel).LowerCamelComputed
-				if sch.Optional {
-					fieldType = types.NewPointer(typeSecretKeySelector)
-					jsonTag += ",omitempty"
+
+				switch fieldType.String() {
+				case "string", "*string":
+					fieldType = typeSecretKeySelector
+					if sch.Optional {
+						fieldType = types.NewPointer(typeSecretKeySelector)
+						jsonTag += ",omitempty"
+					}
+				case "map[string]string":
+					// The whole secret is referenced and every key/value pair
+					// within it is used to populate the map.
+					fieldType = typeSecretReference
+					if sch.Optional {
+						fieldType = types.NewPointer(typeSecretReference)
+						jsonTag += ",omitempty"
+					}
+				case "[]string":
+					// Maps and slices are already pointers, so we don't wrap
+					// this one even if it's optional.
+					fieldType = types.NewSlice(typeSecretKeySelector)
+					if sch.Optional {
+						jsonTag += ",omitempty"
+					}
+				default:
+					return nil, nil, fmt.Errorf(`got type %q for field %q, only types "string", "*string", "map[string]string" and "[]string" supported as sensitive`, fieldType.String(), fieldNameCamel)
 				}
 			}
 			field := types.NewField(token.NoPos, g.Package, fieldNameCamel, fieldType, false)
@@ -0,0 +1,114 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schemagen generates randomized terraform-plugin-sdk v2
+// *schema.Resource trees for use in property-based tests of
+// github.com/crossplane/terrajet/pkg/types.
+package schemagen
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"pgregory.net/rapid"
+)
+
+// MaxDepth bounds how deeply nested a generated schema tree can get, so that
+// every generated resource is guaranteed to terminate.
+const MaxDepth = 3
+
+var scalarTypes = []schema.ValueType{schema.TypeBool, schema.TypeFloat, schema.TypeInt, schema.TypeString}
+
+// Resource generates a randomized *schema.Resource with up to 5 top-level
+// fields, recursing into nested Elem resources up to depth.
+func Resource(t *rapid.T, depth int) *schema.Resource {
+	n := rapid.IntRange(0, 5).Draw(t, "numFields").(int)
+	res := &schema.Resource{Schema: make(map[string]*schema.Schema, n)}
+	for i := 0; i < n; i++ {
+		res.Schema[fmt.Sprintf("field_%d", i)] = Field(t, depth)
+	}
+	return res
+}
+
+// Field generates a single randomized *schema.Schema, mixing scalar types,
+// collections (TypeMap/TypeList/TypeSet) and the Optional/Required/Computed
+// permutations real provider schemas exercise. Sensitive is randomly set on
+// fields of every scalar type (string being the only one types.Builder
+// actually supports, so the rest exercise its clean-failure path) and on
+// map/list/set-of-string fields (types.Builder's sensitive collection
+// support).
+func Field(t *rapid.T, depth int) *schema.Schema {
+	sch := &schema.Schema{}
+
+	switch rapid.SampledFrom([]string{"required", "optional", "computed", "optionalComputed"}).Draw(t, "cor").(string) {
+	case "required":
+		sch.Required = true
+	case "optional":
+		sch.Optional = true
+	case "computed":
+		sch.Computed = true
+	case "optionalComputed":
+		sch.Optional = true
+		sch.Computed = true
+	}
+
+	switch rapid.SampledFrom([]string{"scalar", "map", "list", "set"}).Draw(t, "kind").(string) {
+	case "scalar":
+		sch.Type = rapid.SampledFrom(scalarTypes).Draw(t, "scalarType").(schema.ValueType)
+		sch.Sensitive = rapid.Bool().Draw(t, "sensitive").(bool)
+	case "map":
+		sch.Type = schema.TypeMap
+		sch.Elem = elem(t, depth)
+		sch.Sensitive = elemIsString(sch.Elem) && rapid.Bool().Draw(t, "sensitive").(bool)
+	case "list":
+		sch.Type = schema.TypeList
+		sch.Elem = elem(t, depth)
+		sch.Sensitive = elemIsString(sch.Elem) && rapid.Bool().Draw(t, "sensitive").(bool)
+	case "set":
+		sch.Type = schema.TypeSet
+		sch.Elem = elem(t, depth)
+		sch.Sensitive = elemIsString(sch.Elem) && rapid.Bool().Draw(t, "sensitive").(bool)
+	}
+	return sch
+}
+
+// elemIsString reports whether e - an Elem produced by elem() - is the
+// scalar schema.TypeString value type.
+func elemIsString(e interface{}) bool {
+	vt, ok := e.(schema.ValueType)
+	return ok && vt == schema.TypeString
+}
+
+// elem generates the Elem of a collection-typed field. While depth remains
+// it is either nil (the unset-Elem edge case, see
+// https://github.com/crossplane/terrajet/issues/177), a scalar
+// schema.ValueType, a *schema.Schema or a nested *schema.Resource; at depth
+// 0 it is always a scalar schema.ValueType.
+func elem(t *rapid.T, depth int) interface{} {
+	if depth <= 0 {
+		return rapid.SampledFrom(scalarTypes).Draw(t, "elemScalar").(schema.ValueType)
+	}
+	switch rapid.SampledFrom([]string{"nil", "valueType", "schema", "resource"}).Draw(t, "elemKind").(string) {
+	case "nil":
+		return nil
+	case "valueType":
+		return rapid.SampledFrom(scalarTypes).Draw(t, "elemScalar").(schema.ValueType)
+	case "schema":
+		return Field(t, depth-1)
+	default:
+		return Resource(t, depth-1)
+	}
+}
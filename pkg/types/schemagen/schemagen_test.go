@@ -0,0 +1,297 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schemagen
+
+import (
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"pgregory.net/rapid"
+
+	"github.com/crossplane/terrajet/pkg/config"
+	tjtypes "github.com/crossplane/terrajet/pkg/types"
+)
+
+// TestBuildArbitrarySchemas drives types.Builder.Build with randomized
+// schema.Resource trees and asserts the invariants it must uphold
+// regardless of the shape of the input schema.
+func TestBuildArbitrarySchemas(t *testing.T) {
+	rapid.Check(t, func(t *rapid.T) {
+		res := Resource(t, MaxDepth)
+		pkg := types.NewPackage("github.com/crossplane/terrajet/test", "test")
+		b := tjtypes.NewBuilder(pkg)
+		cfg := &config.Resource{
+			Kind:              "Test",
+			TerraformResource: res,
+		}
+
+		g, err := b.Build(cfg)
+		if err != nil {
+			// The only documented failure mode for a well-formed schema is
+			// a field marked Sensitive whose type is not string, *string,
+			// map[string]string or []string; anything else is a bug.
+			if !strings.Contains(err.Error(), "only types") {
+				t.Fatalf("unexpected Build error for generated schema: %v", err)
+			}
+			return
+		}
+
+		assertWellFormedAndUnique(t, g.Types)
+	})
+}
+
+// assertWellFormedAndUnique checks that every generated named type is a
+// struct (possibly with zero fields, per buildResource's note on empty
+// schemas such as wafv2EmptySchema), and that no two generated types share a
+// name within the package scope.
+func assertWellFormedAndUnique(t *rapid.T, named []*types.Named) {
+	t.Helper()
+	seen := make(map[string]bool, len(named))
+	for _, n := range named {
+		name := n.Obj().Name()
+		if seen[name] {
+			t.Fatalf("generateTypeName produced a duplicate type name: %s", name)
+		}
+		seen[name] = true
+
+		st, ok := n.Underlying().(*types.Struct)
+		if !ok {
+			t.Fatalf("generated type %s is not a struct: %s", name, n.Underlying())
+		}
+		for i := 0; i < st.NumFields(); i++ {
+			f := st.Field(i)
+			if f.Name() == "" {
+				t.Fatalf("generated type %s has an unnamed field", name)
+			}
+			if f.Type() == nil {
+				t.Fatalf("generated type %s field %s has a nil type", name, f.Name())
+			}
+		}
+	}
+}
+
+// buildSingleField builds a one-field resource with sch as its only field
+// and returns the resulting Generated, for tests that need to assert on a
+// specific, hand-picked field shape rather than an arbitrary one.
+func buildSingleField(t *testing.T, sch *schema.Schema) (tjtypes.Generated, error) {
+	t.Helper()
+	res := &schema.Resource{Schema: map[string]*schema.Schema{"test_field": sch}}
+	pkg := types.NewPackage("github.com/crossplane/terrajet/test", "test")
+	cfg := &config.Resource{Kind: "Test", TerraformResource: res}
+	return tjtypes.NewBuilder(pkg).Build(cfg)
+}
+
+// buildSingleFieldWithConfig is buildSingleField but also returns the
+// config.Resource used to build it, for tests that need to inspect state
+// Build recorded on cfg as a side effect (e.g. cfg.Sensitive).
+func buildSingleFieldWithConfig(t *testing.T, sch *schema.Schema) (tjtypes.Generated, *config.Resource, error) {
+	t.Helper()
+	res := &schema.Resource{Schema: map[string]*schema.Schema{"test_field": sch}}
+	pkg := types.NewPackage("github.com/crossplane/terrajet/test", "test")
+	cfg := &config.Resource{Kind: "Test", TerraformResource: res}
+	g, err := tjtypes.NewBuilder(pkg).Build(cfg)
+	return g, cfg, err
+}
+
+// paramAndObsTypes returns the top-level TestParameters/TestObservation
+// types out of g.Types.
+func paramAndObsTypes(t *testing.T, g tjtypes.Generated) (param, obs *types.Struct) {
+	t.Helper()
+	for _, n := range g.Types {
+		switch n.Obj().Name() {
+		case "TestParameters":
+			param = n.Underlying().(*types.Struct)
+		case "TestObservation":
+			obs = n.Underlying().(*types.Struct)
+		}
+	}
+	if param == nil || obs == nil {
+		t.Fatalf("expected both TestParameters and TestObservation among generated types, got %v", g.Types)
+	}
+	return param, obs
+}
+
+// TestParameterObservationSplit checks that a single scalar field lands in
+// the Parameters or Observation struct exactly as isObservation (Computed
+// && !Optional) dictates.
+func TestParameterObservationSplit(t *testing.T) {
+	cases := map[string]struct {
+		sch            *schema.Schema
+		wantParamField bool
+		wantObsField   bool
+	}{
+		"ComputedOnlyIsObservation": {
+			sch:          &schema.Schema{Type: schema.TypeString, Computed: true},
+			wantObsField: true,
+		},
+		"OptionalIsParameter": {
+			sch:            &schema.Schema{Type: schema.TypeString, Optional: true},
+			wantParamField: true,
+		},
+		"RequiredIsParameter": {
+			sch:            &schema.Schema{Type: schema.TypeString, Required: true},
+			wantParamField: true,
+		},
+		"OptionalComputedIsParameter": {
+			sch:            &schema.Schema{Type: schema.TypeString, Optional: true, Computed: true},
+			wantParamField: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			g, err := buildSingleField(t, tc.sch)
+			if err != nil {
+				t.Fatalf("unexpected Build error: %v", err)
+			}
+			param, obs := paramAndObsTypes(t, g)
+			if got := param.NumFields() == 1; got != tc.wantParamField {
+				t.Fatalf("TestParameters.NumFields() = %d, wantParamField = %v", param.NumFields(), tc.wantParamField)
+			}
+			if got := obs.NumFields() == 1; got != tc.wantObsField {
+				t.Fatalf("TestObservation.NumFields() = %d, wantObsField = %v", obs.NumFields(), tc.wantObsField)
+			}
+		})
+	}
+}
+
+// TestSensitiveFieldTypes checks that sensitive string, map[string]string
+// and []string fields are rewritten to the SecretKeySelector/SecretReference
+// types Build is documented to produce, and that a sensitive field of any
+// other type fails Build cleanly rather than panicking.
+func TestSensitiveFieldTypes(t *testing.T) {
+	cases := map[string]struct {
+		sch        *schema.Schema
+		wantErr    bool
+		wantTypeOf string // Obj().Name() of the (possibly pointer/slice-wrapped) field type
+	}{
+		"SensitiveOptionalString": {
+			sch:        &schema.Schema{Type: schema.TypeString, Optional: true, Sensitive: true},
+			wantTypeOf: "SecretKeySelector",
+		},
+		"SensitiveRequiredString": {
+			sch:        &schema.Schema{Type: schema.TypeString, Required: true, Sensitive: true},
+			wantTypeOf: "SecretKeySelector",
+		},
+		"SensitiveOptionalMapOfString": {
+			sch:        &schema.Schema{Type: schema.TypeMap, Elem: schema.TypeString, Optional: true, Sensitive: true},
+			wantTypeOf: "SecretReference",
+		},
+		"SensitiveListOfString": {
+			sch:        &schema.Schema{Type: schema.TypeList, Elem: schema.TypeString, Optional: true, Sensitive: true},
+			wantTypeOf: "SecretKeySelector",
+		},
+		"SensitiveBoolFailsCleanly": {
+			sch:     &schema.Schema{Type: schema.TypeBool, Optional: true, Sensitive: true},
+			wantErr: true,
+		},
+		"SensitiveMapOfBoolFailsCleanly": {
+			sch:     &schema.Schema{Type: schema.TypeMap, Elem: schema.TypeBool, Optional: true, Sensitive: true},
+			wantErr: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			g, err := buildSingleField(t, tc.sch)
+			if tc.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "only types") {
+					t.Fatalf("expected a clean 'only types' Build error, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected Build error: %v", err)
+			}
+			param, _ := paramAndObsTypes(t, g)
+			if param.NumFields() != 1 {
+				t.Fatalf("expected exactly one field on TestParameters, got %d", param.NumFields())
+			}
+			if got := namedTypeName(param.Field(0).Type()); got != tc.wantTypeOf {
+				t.Fatalf("field type = %s, want a type named %s", got, tc.wantTypeOf)
+			}
+		})
+	}
+}
+
+// namedTypeName unwraps pointers and slices to return the Obj().Name() of
+// the innermost *types.Named, or "" if there isn't one.
+func namedTypeName(t types.Type) string {
+	switch v := t.(type) {
+	case *types.Pointer:
+		return namedTypeName(v.Elem())
+	case *types.Slice:
+		return namedTypeName(v.Elem())
+	case *types.Named:
+		return v.Obj().Name()
+	default:
+		return ""
+	}
+}
+
+// TestSensitiveFieldPathRegistration checks that a sensitive field's
+// Terraform path is registered with cfg.Sensitive, in a form that survives
+// a fieldpath.Segments round trip (i.e. parsing it back with fieldpath.Parse
+// reproduces the exact same path string), which is what the runtime's
+// secret-merge logic relies on to find the field it should fill in at apply
+// time. It also checks that a sensitive observation field - which
+// buildResource drops from the generated Observation struct - is still
+// registered, since its value is still stored in the connection details
+// secret despite never appearing as a Go field.
+func TestSensitiveFieldPathRegistration(t *testing.T) {
+	cases := map[string]struct {
+		sch        *schema.Schema
+		wantPrefix string
+	}{
+		"Parameter": {
+			sch:        &schema.Schema{Type: schema.TypeString, Optional: true, Sensitive: true},
+			wantPrefix: "spec.forProvider.",
+		},
+		"Observation": {
+			sch:        &schema.Schema{Type: schema.TypeString, Computed: true, Sensitive: true},
+			wantPrefix: "status.atProvider.",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, cfg, err := buildSingleFieldWithConfig(t, tc.sch)
+			if err != nil {
+				t.Fatalf("unexpected Build error: %v", err)
+			}
+
+			paths := cfg.Sensitive.GetFieldPaths()
+			xp, ok := paths["test_field"]
+			if !ok {
+				t.Fatalf("expected cfg.Sensitive to have registered a path for test_field, got %v", paths)
+			}
+			if !strings.HasPrefix(xp, tc.wantPrefix) {
+				t.Fatalf("registered xpPath %q does not start with %q", xp, tc.wantPrefix)
+			}
+
+			for _, p := range []string{"test_field", xp} {
+				segs, err := fieldpath.Parse(p)
+				if err != nil {
+					t.Fatalf("registered path %q does not parse as a fieldpath: %v", p, err)
+				}
+				if got := segs.String(); got != p {
+					t.Fatalf("fieldpath round trip changed %q into %q", p, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	schemav1 "github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// ConvertResource converts a terraform-plugin-sdk v1 *schema.Resource into
+// its v2 equivalent, recursing into nested Elem resources and schemas.
+func ConvertResource(r *schemav1.Resource) (*schema.Resource, error) {
+	if r == nil {
+		return nil, nil
+	}
+	out := &schema.Resource{
+		Schema: make(map[string]*schema.Schema, len(r.Schema)),
+	}
+	for fieldName, s := range r.Schema {
+		cs, err := convertSchemaV1(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot convert schema of field %s", fieldName)
+		}
+		out.Schema[fieldName] = cs
+	}
+	return out, nil
+}
+
+func convertSchemaV1(s *schemav1.Schema) (*schema.Schema, error) {
+	if s == nil {
+		return nil, nil
+	}
+	out := &schema.Schema{
+		Type:          convertValueTypeV1(s.Type),
+		Required:      s.Required,
+		Optional:      s.Optional,
+		Computed:      s.Computed,
+		ForceNew:      s.ForceNew,
+		Description:   s.Description,
+		InputDefault:  s.InputDefault,
+		MaxItems:      s.MaxItems,
+		MinItems:      s.MinItems,
+		ComputedWhen:  s.ComputedWhen,
+		ConflictsWith: s.ConflictsWith,
+		Deprecated:    s.Deprecated,
+		Removed:       s.Removed,
+		Sensitive:     s.Sensitive,
+	}
+	// v1's ValidateFunc and v2's ValidateDiagFunc are distinct types; only
+	// the former exists on v1 schemas, and its signature is unchanged in
+	// v2, so a straight conversion is sufficient.
+	if s.ValidateFunc != nil {
+		out.ValidateFunc = schema.SchemaValidateFunc(s.ValidateFunc)
+	}
+	// v1 has no notion of ConfigMode (a v2-only addition for nested blocks
+	// that are Computed-only); leaving it unset keeps the v2 default
+	// (SchemaConfigModeAuto), which is the closest equivalent behavior.
+
+	switch et := s.Elem.(type) {
+	case *schemav1.Resource:
+		cr, err := ConvertResource(et)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot convert elem resource")
+		}
+		out.Elem = cr
+	case *schemav1.Schema:
+		ce, err := convertSchemaV1(et)
+		if err != nil {
+			return nil, errors.Wrap(err, "cannot convert elem schema")
+		}
+		out.Elem = ce
+	case schemav1.ValueType:
+		out.Elem = convertValueTypeV1(et)
+	case nil:
+		// Elem left unset, matching the "unset Elem" case buildSchema
+		// already handles for v2 resources.
+	default:
+		return nil, errors.Errorf("elem type %T is neither a v1 Resource, Schema nor ValueType", et)
+	}
+
+	return out, nil
+}
+
+func convertValueTypeV1(t schemav1.ValueType) schema.ValueType {
+	switch t {
+	case schemav1.TypeBool:
+		return schema.TypeBool
+	case schemav1.TypeInt:
+		return schema.TypeInt
+	case schemav1.TypeFloat:
+		return schema.TypeFloat
+	case schemav1.TypeString:
+		return schema.TypeString
+	case schemav1.TypeList:
+		return schema.TypeList
+	case schemav1.TypeMap:
+		return schema.TypeMap
+	case schemav1.TypeSet:
+		return schema.TypeSet
+	default:
+		return schema.TypeInvalid
+	}
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2021 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	"go/token"
+	"go/types"
+)
+
+// typeSecretReference is the go/types representation of
+// github.com/crossplane/crossplane-runtime/apis/common/v1.SecretReference,
+// used as the generated Go type for a sensitive map[string]string field,
+// whose entries are populated from the whole referenced Kubernetes Secret.
+var typeSecretReference = func() *types.Named {
+	pkg := types.NewPackage("github.com/crossplane/crossplane-runtime/apis/common/v1", "v1")
+	st := types.NewStruct([]*types.Var{
+		types.NewField(token.NoPos, pkg, "Name", types.Typ[types.String], false),
+		types.NewField(token.NoPos, pkg, "Namespace", types.Typ[types.String], false),
+	}, []string{`json:"name"`, `json:"namespace"`})
+	name := types.NewTypeName(token.NoPos, pkg, "SecretReference", nil)
+	return types.NewNamed(name, st, nil)
+}()